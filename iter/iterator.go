@@ -0,0 +1,65 @@
+package iter
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/xiaoshao9704/conc"
+)
+
+// Iterator can be used to configure the behaviour of ForEach and
+// ForEachIdx. The zero value is safe to use with reasonable defaults.
+//
+// Iterator is also safe for reuse and concurrent use.
+type Iterator[T any] struct {
+	// MaxGoroutines controls the maximum number of goroutines
+	// to use on this Iterator's methods.
+	//
+	// If unset, MaxGoroutines defaults to runtime.GOMAXPROCS(0).
+	MaxGoroutines int
+}
+
+// ForEachIdx is the same as ForEach except it also provides the index of
+// the element to the callback.
+func (iter Iterator[T]) ForEachIdx(input []T, f func(int, *T)) {
+	numInput := len(input)
+	maxGoroutines := iter.goroutines(numInput)
+
+	var idx atomic.Int64
+	var wg conc.WaitGroup
+	for i := 0; i < maxGoroutines; i++ {
+		wg.Go(func() {
+			for {
+				i := int(idx.Add(1) - 1)
+				if i >= numInput {
+					return
+				}
+				f(i, &input[i])
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// ForEach executes f in parallel over each element in input, using up to
+// this Iterator's configured maximum number of goroutines.
+func (iter Iterator[T]) ForEach(input []T, f func(*T)) {
+	iter.ForEachIdx(input, func(_ int, t *T) { f(t) })
+}
+
+// goroutines returns the number of goroutines this Iterator should use for
+// an input of the given length.
+func (iter Iterator[T]) goroutines(numInput int) int {
+	n := iter.MaxGoroutines
+	if n == 0 {
+		n = defaultMaxGoroutines()
+	}
+	if n > numInput {
+		n = numInput
+	}
+	return n
+}
+
+func defaultMaxGoroutines() int {
+	return runtime.GOMAXPROCS(0)
+}
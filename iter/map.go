@@ -16,6 +16,13 @@ type Mapper[T, R any] struct {
 	// It can be used to configure the maximum number of goroutines
 	// that Mapper methods can use.
 	Iterator[T]
+
+	// Ordered controls the order in which MapStream emits results.
+	// If false (the default), results are emitted in completion order
+	// for maximum throughput. If true, results are emitted in input-index
+	// order, which may hold back completed results while earlier ones are
+	// still in flight.
+	Ordered bool
 }
 
 // Map applies f to each element of input, returning the mapped result.
@@ -0,0 +1,66 @@
+package iter
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/xiaoshao9704/conc"
+)
+
+// MapErrContext is like MapErr, but each call to f is passed a context that
+// is cancelled as soon as any element returns a non-nil error, so a
+// long-running mapper can stop scheduling new work instead of driving every
+// remaining element to completion.
+//
+// MapErrContext uses Iterator to perform the iteration, which always uses
+// at most runtime.GOMAXPROCS goroutines. For a configurable goroutine
+// limit, use a custom Mapper.
+func MapErrContext[T, R any](ctx context.Context, input []T, f func(context.Context, *T) (R, error)) ([]R, error) {
+	return Mapper[T, R]{}.MapErrContext(ctx, input, f)
+}
+
+// MapErrContext is like MapErr, but each call to f is passed a context that
+// is cancelled as soon as any element returns a non-nil error, so a
+// long-running mapper can stop scheduling new work instead of driving every
+// remaining element to completion.
+//
+// MapErrContext uses Iterator to perform the iteration, using up to the
+// configured Iterator's maximum number of goroutines.
+func (m Mapper[T, R]) MapErrContext(ctx context.Context, input []T, f func(context.Context, *T) (R, error)) ([]R, error) {
+	var (
+		res           = make([]R, len(input))
+		numInput      = len(input)
+		maxGoroutines = m.goroutines(numInput)
+		idx           atomic.Int64
+	)
+
+	g := conc.NewWaitGroupContext(ctx)
+	// MapErrContext combines every element's error, same as MapErr.
+	g.AllErrors = true
+
+	for w := 0; w < maxGoroutines; w++ {
+		g.Go(func(ctx context.Context) error {
+			for {
+				select {
+				case <-ctx.Done():
+					// A sibling failed (or the parent context was
+					// cancelled); stop scheduling new work.
+					return nil
+				default:
+				}
+
+				i := int(idx.Add(1) - 1)
+				if i >= numInput {
+					return nil
+				}
+
+				var err error
+				res[i], err = f(ctx, &input[i])
+				if err != nil {
+					return err
+				}
+			}
+		})
+	}
+	return res, g.Wait()
+}
@@ -0,0 +1,73 @@
+package iter
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapErrContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("maps every element", func(t *testing.T) {
+		t.Parallel()
+		input := []int{1, 2, 3, 4, 5}
+		res, err := MapErrContext(context.Background(), input, func(ctx context.Context, i *int) (int, error) {
+			return *i * 2, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 4, 6, 8, 10}, res)
+	})
+
+	t.Run("cancels siblings on first failure", func(t *testing.T) {
+		t.Parallel()
+		m := Mapper[int, int]{Iterator: Iterator[int]{MaxGoroutines: 1}}
+		input := []int{1, 2, 3, 4, 5}
+		boom := errors.New("boom")
+
+		var ran atomic.Int64
+		_, err := m.MapErrContext(context.Background(), input, func(ctx context.Context, i *int) (int, error) {
+			if *i == 2 {
+				return 0, boom
+			}
+			ran.Add(1)
+			return *i, nil
+		})
+
+		require.ErrorIs(t, err, boom)
+		// Only element 1 should have run before element 2 failed and
+		// cancelled the rest; with MaxGoroutines: 1 this is deterministic.
+		require.Equal(t, int64(1), ran.Load())
+	})
+
+	t.Run("parent cancellation stops the mapper", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		input := make([]int, 100)
+		_, err := MapErrContext(ctx, input, func(ctx context.Context, i *int) (int, error) {
+			cancel()
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("times out via context deadline", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		input := make([]int, 100)
+		_, err := MapErrContext(ctx, input, func(ctx context.Context, i *int) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
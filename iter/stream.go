@@ -0,0 +1,176 @@
+package iter
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/xiaoshao9704/conc"
+	"github.com/xiaoshao9704/conc/panics"
+)
+
+// Result is the outcome of applying f to a single element in a call to
+// MapStream. Err holds either the error returned by f or, if f panicked,
+// the recovered panic -- either way, a panic for one element never tears
+// down the rest of the pipeline.
+type Result[R any] struct {
+	Value R
+	Err   error
+}
+
+// MapStream is like MapErr, but instead of waiting for the whole input to
+// finish, it streams each Result back over the returned channel as soon as
+// it's available, while still respecting the Iterator's configured maximum
+// number of goroutines.
+//
+// MapStream uses Iterator to perform the iteration, which always uses at
+// most runtime.GOMAXPROCS goroutines. For a configurable goroutine limit,
+// or to control result ordering, use a custom Mapper.
+func MapStream[T, R any](input []T, f func(*T) (R, error)) (<-chan Result[R], func()) {
+	return Mapper[T, R]{}.MapStream(input, f)
+}
+
+// MapStream is like MapErr, but instead of waiting for the whole input to
+// finish, it streams each Result back over the returned channel as soon as
+// it's available, while still respecting the Mapper's configured maximum
+// number of goroutines.
+//
+// By default, results are emitted in completion order. Set Mapper.Ordered
+// to emit them in input order instead.
+//
+// The returned cancel func stops scheduling new work, waits for in-flight
+// goroutines to drain, and closes the channel. Callers that stop reading
+// from the channel before it's exhausted must call cancel to avoid leaking
+// goroutines.
+func (m Mapper[T, R]) MapStream(input []T, f func(*T) (R, error)) (<-chan Result[R], func()) {
+	out := make(chan Result[R])
+
+	maxGoroutines := m.goroutines(len(input))
+	if maxGoroutines == 0 {
+		close(out)
+		return out, func() {}
+	}
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	var closeOnce sync.Once
+	cancel := func() {
+		// Stop scheduling new work and wait for any in-flight goroutines to
+		// drain before returning, so the caller can rely on the channel
+		// being fully closed once cancel returns.
+		closeOnce.Do(func() { close(done) })
+		<-finished
+	}
+
+	var idx atomic.Int64
+	next := func() (int, bool) {
+		select {
+		case <-done:
+			return 0, false
+		default:
+		}
+		i := int(idx.Add(1) - 1)
+		return i, i < len(input)
+	}
+
+	apply := func(i int) Result[R] {
+		var (
+			res Result[R]
+			pc  panics.Catcher
+		)
+		pc.Try(func() {
+			res.Value, res.Err = f(&input[i])
+		})
+		if p := pc.Recovered(); p != nil {
+			res.Err = p
+		}
+		return res
+	}
+
+	go func() {
+		defer close(finished)
+		if m.Ordered {
+			m.streamOrdered(maxGoroutines, next, apply, out, done)
+		} else {
+			m.streamUnordered(maxGoroutines, next, apply, out, done)
+		}
+	}()
+
+	return out, cancel
+}
+
+// streamUnordered feeds results to out in whatever order they complete in.
+func (m Mapper[T, R]) streamUnordered(maxGoroutines int, next func() (int, bool), apply func(int) Result[R], out chan<- Result[R], done <-chan struct{}) {
+	var wg conc.WaitGroup
+	for g := 0; g < maxGoroutines; g++ {
+		wg.Go(func() {
+			for {
+				i, ok := next()
+				if !ok {
+					return
+				}
+				select {
+				case <-done:
+					return
+				case out <- apply(i):
+				}
+			}
+		})
+	}
+	wg.Wait()
+	close(out)
+}
+
+// streamOrdered feeds results to out in input-index order, holding
+// out-of-order completions in a small reorder buffer keyed by index.
+func (m Mapper[T, R]) streamOrdered(maxGoroutines int, next func() (int, bool), apply func(int) Result[R], out chan<- Result[R], done <-chan struct{}) {
+	type indexed struct {
+		i   int
+		res Result[R]
+	}
+	completed := make(chan indexed)
+
+	var wg conc.WaitGroup
+	for g := 0; g < maxGoroutines; g++ {
+		wg.Go(func() {
+			for {
+				i, ok := next()
+				if !ok {
+					return
+				}
+				res := apply(i)
+				select {
+				case <-done:
+					return
+				case completed <- indexed{i, res}:
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(completed)
+	}()
+
+	buf := make(map[int]Result[R])
+	wantIdx := 0
+	for c := range completed {
+		buf[c.i] = c.res
+		for {
+			res, ok := buf[wantIdx]
+			if !ok {
+				break
+			}
+			delete(buf, wantIdx)
+			select {
+			case <-done:
+				for range completed {
+				}
+				close(out)
+				return
+			case out <- res:
+			}
+			wantIdx++
+		}
+	}
+	close(out)
+}
@@ -0,0 +1,92 @@
+package iter
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unordered emits every result", func(t *testing.T) {
+		t.Parallel()
+		input := []int{1, 2, 3, 4, 5}
+		out, cancel := MapStream(input, func(i *int) (int, error) {
+			return *i * 2, nil
+		})
+		defer cancel()
+
+		var got []int
+		for r := range out {
+			require.NoError(t, r.Err)
+			got = append(got, r.Value)
+		}
+		sort.Ints(got)
+		require.Equal(t, []int{2, 4, 6, 8, 10}, got)
+	})
+
+	t.Run("ordered emits in input order", func(t *testing.T) {
+		t.Parallel()
+		input := []int{1, 2, 3, 4, 5}
+		m := Mapper[int, int]{Ordered: true}
+		out, cancel := m.MapStream(input, func(i *int) (int, error) {
+			return *i, nil
+		})
+		defer cancel()
+
+		var got []int
+		for r := range out {
+			require.NoError(t, r.Err)
+			got = append(got, r.Value)
+		}
+		require.Equal(t, input, got)
+	})
+
+	t.Run("panics are captured per item", func(t *testing.T) {
+		t.Parallel()
+		input := []int{1, 2, 3}
+		out, cancel := MapStream(input, func(i *int) (int, error) {
+			if *i == 2 {
+				panic("bad item")
+			}
+			return *i, nil
+		})
+		defer cancel()
+
+		var errs, oks int
+		for r := range out {
+			if r.Err != nil {
+				errs++
+				require.Contains(t, r.Err.Error(), "bad item")
+				continue
+			}
+			oks++
+		}
+		require.Equal(t, 1, errs)
+		require.Equal(t, 2, oks)
+	})
+
+	t.Run("cancel stops delivery and drains", func(t *testing.T) {
+		t.Parallel()
+		input := make([]int, 100)
+		out, cancel := MapStream(input, func(i *int) (int, error) {
+			return *i, nil
+		})
+		<-out
+		cancel()
+		_, ok := <-out
+		require.False(t, ok)
+	})
+
+	t.Run("empty input closes immediately", func(t *testing.T) {
+		t.Parallel()
+		out, cancel := MapStream([]int{}, func(i *int) (int, error) {
+			return *i, nil
+		})
+		defer cancel()
+		_, ok := <-out
+		require.False(t, ok)
+	})
+}
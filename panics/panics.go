@@ -0,0 +1,86 @@
+// Package panics provides utilities for gracefully handling panics
+// that occur in other goroutines.
+package panics
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// WorkerPanic is the value captured when a goroutine spawned by a Catcher
+// panics. In addition to the original panic value, it carries the stack
+// trace of every goroutine that panicked, so that a panic re-raised from
+// Wait doesn't just point at the Wait call site -- it points at wherever
+// the work actually failed.
+type WorkerPanic struct {
+	// Value is the original value passed to panic() by the first goroutine
+	// to panic.
+	Value any
+	// Stacktraces holds the stack trace captured by runtime/debug.Stack()
+	// at the moment each panic was recovered. Stacktraces[0] corresponds to
+	// Value; any further entries are from later panics that lost the race
+	// to become Value but were still recovered rather than lost.
+	Stacktraces []string
+}
+
+// Error implements the error interface so that a WorkerPanic can be
+// returned from places like WaitGroup.WaitSafe without losing the
+// stack trace(s) of the original panic(s).
+func (p *WorkerPanic) Error() string {
+	msg := fmt.Sprintf("panic: %v", p.Value)
+	for _, st := range p.Stacktraces {
+		msg += "\n" + st
+	}
+	return msg
+}
+
+// Catcher is used to catch panics. You can execute a function with Try,
+// which will catch any spawned panic. Try can be called any number of
+// times, from any number of goroutines. Once all calls to Try have
+// completed, you can get the value of the recovered panic, if any, with
+// Recovered.
+type Catcher struct {
+	mu        sync.Mutex
+	recovered *WorkerPanic
+}
+
+// Try executes f, catching and storing any panic it may spawn, along with
+// the stack trace of the goroutine that panicked.
+func (p *Catcher) Try(f func()) {
+	defer p.tryRecover()
+	f()
+}
+
+func (p *Catcher) tryRecover() {
+	val := recover()
+	if val == nil {
+		return
+	}
+	stacktrace := string(debug.Stack())
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.recovered == nil {
+		p.recovered = &WorkerPanic{Value: val, Stacktraces: []string{stacktrace}}
+		return
+	}
+	p.recovered.Stacktraces = append(p.recovered.Stacktraces, stacktrace)
+}
+
+// Repanic panics if any calls to Try caught a panic. It will panic with a
+// *WorkerPanic holding the value of the first panic caught and the stack
+// trace(s) of every panic that was caught.
+func (p *Catcher) Repanic() {
+	if val := p.Recovered(); val != nil {
+		panic(val)
+	}
+}
+
+// Recovered returns a *WorkerPanic if any calls to Try caught a panic,
+// otherwise returns nil.
+func (p *Catcher) Recovered() *WorkerPanic {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.recovered
+}
@@ -0,0 +1,58 @@
+package panics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no panic", func(t *testing.T) {
+		t.Parallel()
+		var c Catcher
+		c.Try(func() {})
+		require.Nil(t, c.Recovered())
+		require.NotPanics(t, c.Repanic)
+	})
+
+	t.Run("captures value and stacktrace", func(t *testing.T) {
+		t.Parallel()
+		var c Catcher
+		c.Try(func() { panic("oh no") })
+
+		p := c.Recovered()
+		require.NotNil(t, p)
+		require.Equal(t, "oh no", p.Value)
+		require.Len(t, p.Stacktraces, 1)
+		require.Contains(t, p.Stacktraces[0], "panics_test.go")
+	})
+
+	t.Run("keeps first value, appends later stacktraces", func(t *testing.T) {
+		t.Parallel()
+		var c Catcher
+		c.Try(func() { panic("first") })
+		c.Try(func() { panic("second") })
+
+		p := c.Recovered()
+		require.NotNil(t, p)
+		require.Equal(t, "first", p.Value)
+		require.Len(t, p.Stacktraces, 2)
+	})
+
+	t.Run("repanic includes stacktrace", func(t *testing.T) {
+		t.Parallel()
+		var c Catcher
+		c.Try(func() { panic("boom") })
+
+		defer func() {
+			val := recover()
+			p, ok := val.(*WorkerPanic)
+			require.True(t, ok)
+			require.Contains(t, p.Error(), "boom")
+			require.Contains(t, p.Error(), "panics_test.go")
+		}()
+		c.Repanic()
+	})
+}
@@ -0,0 +1,154 @@
+// Package conc implements utilities for structured concurrency, with the
+// goal of making concurrent code easier to get right, and simpler to read.
+package conc
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/xiaoshao9704/conc/panics"
+)
+
+// NewWaitGroup creates a new WaitGroup.
+func NewWaitGroup() *WaitGroup {
+	return &WaitGroup{}
+}
+
+// waitGroupState is the lifecycle of a single generation of a WaitGroup.
+type waitGroupState uint32
+
+const (
+	// stateIdle means Go can be called freely.
+	stateIdle waitGroupState = iota
+	// stateWaiting means a Wait or WaitSafe call is in progress.
+	stateWaiting
+	// stateDone means a Wait or WaitSafe call has returned, and the
+	// WaitGroup must be Reset before it can be reused.
+	stateDone
+)
+
+// WaitGroup is the primary building block for scoped concurrency.
+// Goroutines can be spawned in the WaitGroup with the Go method,
+// and calling Wait() will ensure that each goroutine exits before
+// continuing. Any panics in a child goroutine will be caught and
+// propagated to the caller of Wait(), including the stack trace of the
+// goroutine that originally panicked.
+//
+// Unlike sync.WaitGroup, a WaitGroup must not be reused until the
+// in-progress Wait (or WaitSafe) has returned; calling Go after a Wait has
+// started, or calling Wait/WaitSafe more than once without an intervening
+// Reset, panics instead of silently racing. Call Reset once Wait has
+// returned to begin a new generation and reuse the WaitGroup.
+//
+// The zero value of WaitGroup is safe to use.
+type WaitGroup struct {
+	wg    sync.WaitGroup
+	pc    panics.Catcher
+	count atomic.Int64
+	state atomic.Uint32
+
+	// generation is bumped by Reset. It's recorded alongside each spawn site
+	// (see the concdebug build tag) so a misuse dump can tell which
+	// generation of the WaitGroup a still-running goroutine belongs to.
+	generation atomic.Uint64
+
+	nextSpawnID atomic.Uint64
+	debugMu     sync.Mutex
+	spawned     map[uint64]spawnSite
+}
+
+// Go spawns a new goroutine in the WaitGroup. Go panics if called after a
+// Wait or WaitSafe call on the same generation has started; see Reset.
+func (h *WaitGroup) Go(f func()) {
+	switch waitGroupState(h.state.Load()) {
+	case stateWaiting:
+		panic("conc: WaitGroup misuse: Go called after Wait has started; a WaitGroup must not be reused until the in-progress Wait returns" + h.debugDump())
+	case stateDone:
+		panic("conc: WaitGroup misuse: Go called on a WaitGroup whose previous Wait has already returned; call Reset before reusing it" + h.debugDump())
+	}
+
+	h.count.Add(1)
+	id := h.nextSpawnID.Add(1)
+	if debugEnabled {
+		h.recordSpawn(id)
+	}
+	h.wg.Add(1)
+	go func() {
+		defer func() {
+			if debugEnabled {
+				h.removeSpawn(id)
+			}
+			h.count.Add(-1)
+			h.wg.Done()
+		}()
+		h.pc.Try(f)
+	}()
+}
+
+// Wait will block until all goroutines spawned with Go exit and will
+// propagate any panics spawned in a child goroutine. The panic value is a
+// *panics.WorkerPanic carrying the stack trace(s) of the goroutine(s) that
+// originally panicked.
+//
+// Calling Wait more than once, or concurrently with another Wait/WaitSafe
+// call, without an intervening Reset is a misuse and panics. Once Wait
+// returns, the WaitGroup must be Reset before Go can be called again.
+func (h *WaitGroup) Wait() {
+	h.enterWaiting()
+	h.wg.Wait()
+	h.state.Store(uint32(stateDone))
+	// Propagate a panic if we caught one from a child goroutine.
+	h.pc.Repanic()
+}
+
+// WaitSafe will block until all goroutines spawned with Go exit, and will
+// return an error carrying the recovered panic, if any, instead of
+// re-panicking. The error's message embeds the stack trace(s) captured at
+// the point of the original panic(s).
+//
+// Calling WaitSafe more than once, or concurrently with another
+// Wait/WaitSafe call, without an intervening Reset is a misuse and panics.
+// Once WaitSafe returns, the WaitGroup must be Reset before Go can be
+// called again.
+func (h *WaitGroup) WaitSafe() error {
+	h.enterWaiting()
+	h.wg.Wait()
+	h.state.Store(uint32(stateDone))
+	if p := h.pc.Recovered(); p != nil {
+		return p
+	}
+	return nil
+}
+
+// enterWaiting transitions the WaitGroup from stateIdle to stateWaiting, or
+// panics if it isn't idle -- i.e. if Wait or WaitSafe has already been
+// called on this generation.
+func (h *WaitGroup) enterWaiting() {
+	if !h.state.CompareAndSwap(uint32(stateIdle), uint32(stateWaiting)) {
+		panic("conc: WaitGroup misuse: Wait or WaitSafe called more than once, or concurrently, without an intervening Reset" + h.debugDump())
+	}
+}
+
+// Reset prepares the WaitGroup for reuse, beginning a new generation. It
+// must only be called after a previous call to Wait or WaitSafe has
+// returned; calling it while a Wait is in progress, or before any Wait has
+// been called at all, panics.
+func (h *WaitGroup) Reset() {
+	if waitGroupState(h.state.Load()) != stateDone {
+		panic("conc: WaitGroup misuse: Reset called before a previous Wait or WaitSafe returned")
+	}
+	h.generation.Add(1)
+	h.pc = panics.Catcher{}
+	if debugEnabled {
+		h.debugMu.Lock()
+		h.spawned = nil
+		h.debugMu.Unlock()
+	}
+	h.state.Store(uint32(stateIdle))
+}
+
+// InFlight returns the number of goroutines spawned by Go that have not yet
+// returned. It's intended for observability, e.g. exporting as a gauge.
+func (h *WaitGroup) InFlight() int {
+	return int(h.count.Load())
+}
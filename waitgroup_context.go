@@ -0,0 +1,102 @@
+package conc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/lib/errors"
+)
+
+// NewWaitGroupContext creates a new WaitGroupContext that derives a
+// cancellable child of ctx. The child context is cancelled with the
+// triggering error as its cause as soon as the first task passed to Go
+// returns a non-nil error or panics.
+func NewWaitGroupContext(ctx context.Context) *WaitGroupContext {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &WaitGroupContext{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// WaitGroupContext is a WaitGroup for tasks that can fail. Each task spawned
+// with Go is handed a context.Context that is cancelled as soon as any task
+// in the group returns an error or panics, so sibling tasks that observe
+// ctx.Done() can exit early instead of doing wasted work. This is the same
+// pattern as golang.org/x/sync/errgroup, adapted to use WaitGroup's panic
+// handling.
+//
+// Unlike WaitGroup, the zero value is not usable: construct one with
+// NewWaitGroupContext. Using the zero value panics with a clear message
+// rather than nil-dereferencing.
+type WaitGroupContext struct {
+	wg     WaitGroup
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	// AllErrors controls what Wait returns when more than one task fails.
+	// If false (the default, matching errgroup.Group), Wait returns only
+	// the first error encountered. If true, Wait returns every task's
+	// error joined together.
+	AllErrors bool
+
+	mu   sync.Mutex
+	errs error
+}
+
+// Go spawns a new task in the group. f is passed the group's context, which
+// is cancelled as soon as any task spawned in the group returns an error or
+// panics.
+//
+// Go panics if called on the zero value of WaitGroupContext; construct one
+// with NewWaitGroupContext first.
+func (g *WaitGroupContext) Go(f func(ctx context.Context) error) {
+	g.checkConstructed()
+	g.wg.Go(func() {
+		defer func() {
+			if v := recover(); v != nil {
+				g.cancel(fmt.Errorf("panic: %v", v))
+				panic(v) // re-thrown so WaitGroup's Catcher records the stack trace
+			}
+		}()
+		if err := f(g.ctx); err != nil {
+			g.mu.Lock()
+			switch {
+			case g.AllErrors:
+				g.errs = errors.Append(g.errs, err)
+			case g.errs == nil:
+				g.errs = err
+			}
+			g.mu.Unlock()
+			g.cancel(err)
+		}
+	})
+}
+
+// Wait blocks until every task spawned with Go has returned, then cancels
+// the group's context (if it hasn't already been cancelled) and returns the
+// error of the tasks that failed, or nil if none did: the first error
+// encountered by default, or every task's error joined together if
+// AllErrors is set. If any task panicked, Wait re-panics with the original
+// stack trace instead of returning.
+//
+// Wait panics if called on the zero value of WaitGroupContext; construct
+// one with NewWaitGroupContext first.
+func (g *WaitGroupContext) Wait() error {
+	g.checkConstructed()
+	g.wg.Wait() // re-panics with the original stack trace, if any
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cancel(g.errs)
+	return g.errs
+}
+
+// checkConstructed panics with a clear message if g is the zero value of
+// WaitGroupContext, instead of letting callers hit a bare nil-pointer panic
+// on g.ctx/g.cancel further down the call stack.
+func (g *WaitGroupContext) checkConstructed() {
+	if g.ctx == nil {
+		panic("conc: WaitGroupContext misuse: use NewWaitGroupContext to construct a WaitGroupContext; the zero value is not usable")
+	}
+}
@@ -0,0 +1,151 @@
+package conc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitGroupContext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all spawned run", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		var n atomic.Int64
+		for i := 0; i < 10; i++ {
+			g.Go(func(ctx context.Context) error {
+				n.Add(1)
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+		require.Equal(t, int64(10), n.Load())
+	})
+
+	t.Run("error cancels siblings", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		boom := errors.New("boom")
+
+		g.Go(func(ctx context.Context) error {
+			return boom
+		})
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := g.Wait()
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("default keeps only the first error", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		errA := errors.New("a failed")
+		errB := errors.New("b failed")
+		started := make(chan struct{})
+
+		g.Go(func(ctx context.Context) error {
+			close(started)
+			return errA
+		})
+		g.Go(func(ctx context.Context) error {
+			<-started
+			<-ctx.Done()
+			return errB
+		})
+
+		err := g.Wait()
+		require.ErrorIs(t, err, errA)
+		require.NotErrorIs(t, err, errB)
+	})
+
+	t.Run("AllErrors joins every failure", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		g.AllErrors = true
+		errA := errors.New("a failed")
+		errB := errors.New("b failed")
+
+		g.Go(func(ctx context.Context) error { return errA })
+		g.Go(func(ctx context.Context) error { return errB })
+
+		err := g.Wait()
+		require.ErrorIs(t, err, errA)
+		require.ErrorIs(t, err, errB)
+	})
+
+	t.Run("panic is re-raised with stack trace", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		g.Go(func(ctx context.Context) error {
+			panic("super bad thing")
+		})
+
+		defer func() {
+			val := recover()
+			err, ok := val.(error)
+			require.True(t, ok)
+			require.Contains(t, err.Error(), "super bad thing")
+			require.Contains(t, err.Error(), "waitgroup_context_test.go")
+		}()
+		_ = g.Wait()
+	})
+
+	t.Run("parent cancellation propagates", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		g := NewWaitGroupContext(ctx)
+
+		g.Go(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		cancel()
+
+		err := g.Wait()
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("context is cancelled by the time Wait returns", func(t *testing.T) {
+		t.Parallel()
+		g := NewWaitGroupContext(context.Background())
+		g.Go(func(ctx context.Context) error { return nil })
+		_ = g.Wait()
+
+		select {
+		case <-g.ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("context was not cancelled after Wait returned")
+		}
+	})
+
+	t.Run("zero value panics with a clear message instead of nil-dereferencing", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("Go", func(t *testing.T) {
+			t.Parallel()
+			var g WaitGroupContext
+			require.PanicsWithValue(t,
+				"conc: WaitGroupContext misuse: use NewWaitGroupContext to construct a WaitGroupContext; the zero value is not usable",
+				func() { g.Go(func(ctx context.Context) error { return nil }) },
+			)
+		})
+
+		t.Run("Wait", func(t *testing.T) {
+			t.Parallel()
+			var g WaitGroupContext
+			require.PanicsWithValue(t,
+				"conc: WaitGroupContext misuse: use NewWaitGroupContext to construct a WaitGroupContext; the zero value is not usable",
+				func() { _ = g.Wait() },
+			)
+		})
+	})
+}
+
@@ -0,0 +1,68 @@
+//go:build concdebug
+
+package conc
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// debugEnabled is true when built with -tags concdebug, enabling extra
+// bookkeeping that records where each in-flight goroutine was spawned from
+// so that misuse panics can point at the culprit.
+const debugEnabled = true
+
+// spawnSite records the call stack and generation of a call to Go, so it
+// can be reported if the WaitGroup is later misused.
+type spawnSite struct {
+	generation uint64
+	pc         []uintptr
+}
+
+func (h *WaitGroup) recordSpawn(id uint64) {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(3, pc) // skip recordSpawn, Go, and runtime.Callers itself
+
+	h.debugMu.Lock()
+	defer h.debugMu.Unlock()
+	if h.spawned == nil {
+		h.spawned = make(map[uint64]spawnSite)
+	}
+	h.spawned[id] = spawnSite{generation: h.generation.Load(), pc: pc[:n]}
+}
+
+// removeSpawn forgets the spawn site recorded for id, once that goroutine
+// has returned, so debugDump only ever reports goroutines that are actually
+// still in flight.
+func (h *WaitGroup) removeSpawn(id uint64) {
+	h.debugMu.Lock()
+	defer h.debugMu.Unlock()
+	delete(h.spawned, id)
+}
+
+// debugDump renders the spawn site of every still-in-flight goroutine, for
+// inclusion in a misuse panic message.
+func (h *WaitGroup) debugDump() string {
+	h.debugMu.Lock()
+	defer h.debugMu.Unlock()
+
+	if len(h.spawned) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nconc: %d in-flight goroutine(s) spawned from:\n", len(h.spawned))
+	for _, s := range h.spawned {
+		fmt.Fprintf(&sb, "\t[generation %d]\n", s.generation)
+		frames := runtime.CallersFrames(s.pc)
+		for {
+			frame, more := frames.Next()
+			fmt.Fprintf(&sb, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+			if !more {
+				break
+			}
+		}
+	}
+	return sb.String()
+}
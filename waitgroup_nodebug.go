@@ -0,0 +1,19 @@
+//go:build !concdebug
+
+package conc
+
+// debugEnabled is true when built with -tags concdebug, enabling extra
+// bookkeeping that records where each in-flight goroutine was spawned from
+// so that misuse panics can point at the culprit. It's off by default to
+// keep Go as cheap as the stdlib's sync.WaitGroup.Add.
+const debugEnabled = false
+
+// spawnSite is unused outside of -tags concdebug, but its type must exist
+// unconditionally so WaitGroup.spawned can be declared without a build tag.
+type spawnSite struct{}
+
+func (h *WaitGroup) recordSpawn(id uint64) {}
+
+func (h *WaitGroup) removeSpawn(id uint64) {}
+
+func (h *WaitGroup) debugDump() string { return "" }
@@ -139,5 +139,99 @@ func TestWaitGroup(t *testing.T) {
 			require.Contains(t, p.Error(), "super bad thing", p.Error())
 			require.Equal(t, int64(2), i.Load())
 		})
+
+		t.Run("waitsafe error embeds the original stack trace", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {
+				panic("super bad thing")
+			})
+			p := wg.WaitSafe()
+			require.Contains(t, p.Error(), "waitgroup_test.go", p.Error())
+		})
+
+		t.Run("repanic carries original stack trace", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {
+				panic("super bad thing")
+			})
+			defer func() {
+				val := recover()
+				err, ok := val.(error)
+				require.True(t, ok)
+				require.Contains(t, err.Error(), "waitgroup_test.go")
+			}()
+			wg.Wait()
+		})
+	})
+
+	t.Run("misuse", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("go after wait has started panics", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {})
+			wg.state.Store(uint32(stateWaiting))
+			require.Panics(t, func() { wg.Go(func() {}) })
+		})
+
+		t.Run("go after wait has returned panics", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {})
+			wg.Wait()
+			require.Panics(t, func() { wg.Go(func() {}) })
+		})
+
+		t.Run("reset before wait returns panics", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			require.Panics(t, wg.Reset)
+		})
+
+		t.Run("reset allows reuse", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() { panic("first generation") })
+			require.Panics(t, wg.Wait)
+			wg.Reset()
+
+			var ran atomic.Bool
+			wg.Go(func() { ran.Store(true) })
+			wg.Wait()
+			require.True(t, ran.Load())
+		})
+
+		t.Run("wait called again without reset panics", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {})
+			wg.Wait()
+			require.Panics(t, wg.Wait)
+		})
+
+		t.Run("waitsafe called again without reset panics", func(t *testing.T) {
+			t.Parallel()
+			var wg WaitGroup
+			wg.Go(func() {})
+			require.NoError(t, wg.WaitSafe())
+			require.Panics(t, func() { wg.WaitSafe() })
+		})
+	})
+
+	t.Run("InFlight", func(t *testing.T) {
+		t.Parallel()
+		var wg WaitGroup
+		require.Equal(t, 0, wg.InFlight())
+
+		release := make(chan struct{})
+		wg.Go(func() { <-release })
+		require.Equal(t, 1, wg.InFlight())
+
+		close(release)
+		wg.Wait()
+		require.Equal(t, 0, wg.InFlight())
 	})
 }